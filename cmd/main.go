@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/tatlilimon/PFS/internal/cache"
 	"github.com/tatlilimon/PFS/internal/llm"
 )
 
@@ -45,9 +46,19 @@ func showLoadingAnimation(done <-chan struct{}) {
 	}
 }
 
+// printDimmed writes a chunk of streamed model output in a dimmed style, so
+// it reads as in-progress generation rather than the final answer.
+func printDimmed(chunk string) {
+	fmt.Printf("\033[2m%s\033[0m", chunk)
+}
+
 func main() {
 	// Parse command-line arguments.
 	verbose := flag.Bool("verbose", false, "Enable verbose output for debugging")
+	chat := flag.Bool("chat", false, "Enable multi-turn refinement: offer an 'r' option to refine the correction before running it")
+	flag.BoolVar(chat, "i", false, "Shorthand for --chat")
+	tools := flag.Bool("tools", false, "Let the model consult local validators (command existence, man page, --help) via tool calls before answering")
+	noCache := flag.Bool("no-cache", false, "Disable the on-disk correction cache")
 	flag.Parse()
 
 	// Read and parse the last command's info from stdin.
@@ -74,29 +85,82 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Get the configured LLM provider and check connection.
-	provider, err := llm.NewOllamaProvider()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	// Check the on-disk cache before involving the LLM at all, so repeat
+	// typos (and offline use of previously-seen mistakes) don't pay the full
+	// inference cost twice.
+	var correctionCache *cache.Cache
+	cacheKey := cache.Key(info.Command, info.ExitCode, info.Output)
+	if !*noCache {
+		correctionCache, err = cache.New()
+		if err != nil && *verbose {
+			fmt.Fprintf(os.Stderr, "Warning: failed to initialize correction cache: %v\n", err)
+		}
 	}
-	fmt.Printf("✅ Connected to Ollama model: %s\n", provider.ModelName())
 
-	// Get the correction from the LLM with a loading animation.
-	ctx := context.Background()
 	var correction *llm.Correction
-	done := make(chan struct{})
-	go func() {
-		defer close(done)
-		correction, err = provider.GetCorrection(ctx, info.Command, info.Output, info.ExitCode, *verbose)
-	}()
+	if correctionCache != nil {
+		if entry, ok := correctionCache.Get(cacheKey); ok {
+			correction = &llm.Correction{Explanation: entry.Explanation, CorrectedCommand: entry.CorrectedCommand}
+			fmt.Println("💾 Using cached correction.")
+		}
+	}
 
-	showLoadingAnimation(done)
-	<-done
+	var provider llm.Provider
+	ctx := context.Background()
+	if correction == nil {
+		// Get the configured LLM provider and check connection.
+		provider, err = llm.NewProvider()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Connected to LLM model: %s\n", provider.ModelName())
 
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Failed to get correction from LLM: %v\n", err)
-		os.Exit(1)
+		// Get the correction from the LLM. Providers that support streaming print
+		// the response as it arrives (dimmed) instead of a plain loading spinner.
+		if toolProvider, ok := provider.(llm.ToolCallingProvider); *tools && ok {
+			correction, err = toolProvider.GetCorrectionWithTools(ctx, info.Command, info.Output, info.ExitCode, *verbose)
+		} else if streamingProvider, ok := provider.(llm.StreamingProvider); ok {
+			fmt.Println("Asking the llm for your last failed command...")
+			correction, err = streamingProvider.GetCorrectionStream(ctx, info.Command, info.Output, info.ExitCode, *verbose, printDimmed)
+			fmt.Println()
+		} else {
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				correction, err = provider.GetCorrection(ctx, info.Command, info.Output, info.ExitCode, *verbose)
+			}()
+
+			showLoadingAnimation(done)
+			<-done
+		}
+
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to get correction from LLM: %v\n", err)
+			os.Exit(1)
+		}
+
+		if correctionCache != nil && correction.CorrectedCommand != "" {
+			entry := cache.Entry{Explanation: correction.Explanation, CorrectedCommand: correction.CorrectedCommand, StoredAt: time.Now()}
+			if err := correctionCache.Set(cacheKey, entry); err != nil && *verbose {
+				fmt.Fprintf(os.Stderr, "Warning: failed to update correction cache: %v\n", err)
+			}
+		}
+	} else if *chat {
+		// A cache hit skips the LLM entirely, but --chat still needs a
+		// provider to refine against. Fetch one lazily here rather than
+		// eagerly on every cache hit, and degrade to non-refine instead of
+		// failing outright if the provider can't be reached (the cache is
+		// meant to keep repeat typos working offline).
+		provider, err = llm.NewProvider()
+		if err != nil {
+			if *verbose {
+				fmt.Fprintf(os.Stderr, "Warning: --chat refinement unavailable, failed to connect to LLM: %v\n", err)
+			}
+			provider = nil
+		} else if *verbose {
+			fmt.Printf("✅ Connected to LLM model for refinement: %s\n", provider.ModelName())
+		}
 	}
 
 	// Display the correction and ask for confirmation.
@@ -120,8 +184,6 @@ func main() {
 		os.Exit(0)
 	}
 
-	fmt.Print("> Execute this command? (y/n) ")
-
 	// Open /dev/tty for interactive input, separate from stdin
 	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
 	if err != nil {
@@ -131,25 +193,88 @@ func main() {
 	defer tty.Close()
 
 	scanner := bufio.NewScanner(tty)
-	if scanner.Scan() {
+
+	// Multi-turn refinement is only available if the user asked for it and
+	// the configured provider actually supports a Chat method.
+	chatProvider, canRefine := provider.(llm.ChatProvider)
+	canRefine = canRefine && *chat
+	var history []llm.Message
+	if canRefine {
+		history = []llm.Message{
+			{Role: "user", Content: fmt.Sprintf("My command `%s` failed with exit code %d and output:\n%s", info.Command, info.ExitCode, info.Output)},
+			{Role: "assistant", Content: fmt.Sprintf(`{"explanation": %q, "corrected_command": %q}`, correction.Explanation, correction.CorrectedCommand)},
+		}
+	}
+
+	for {
+		if canRefine {
+			fmt.Print("> Execute this command? (y/n/r to refine) ")
+		} else {
+			fmt.Print("> Execute this command? (y/n) ")
+		}
+
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to read user input: %v\n", err)
+				os.Exit(1)
+			}
+			// Nothing was entered, treat as "no"
+			fmt.Println("Aborted.")
+			return
+		}
+
 		answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
-		if answer == "y" || answer == "yes" {
+		switch {
+		case answer == "y" || answer == "yes":
 			// 8. Write the command to the temp file for the shell wrapper to execute.
-			err := os.WriteFile(correctedCmdFile, []byte(correction.CorrectedCommand), 0644)
-			if err != nil {
+			if err := os.WriteFile(correctedCmdFile, []byte(correction.CorrectedCommand), 0644); err != nil {
 				fmt.Fprintf(os.Stderr, "Error: Failed to write corrected command to temp file: %v\n", err)
 				os.Exit(1)
 			}
 			// The shell wrapper will now pick up this file.
-		} else {
+			return
+		case canRefine && answer == "r":
+			fmt.Print("Refine> ")
+			if !scanner.Scan() {
+				fmt.Println("Aborted.")
+				return
+			}
+			refinement := strings.TrimSpace(scanner.Text())
+			if refinement == "" {
+				continue
+			}
+			history = append(history, llm.Message{Role: "user", Content: refinement})
+
+			refined, err := chatProvider.Chat(ctx, history)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to refine correction: %v\n", err)
+				continue
+			}
+			history = append(history, llm.Message{Role: "assistant", Content: fmt.Sprintf(`{"explanation": %q, "corrected_command": %q}`, refined.Explanation, refined.CorrectedCommand)})
+
+			// Re-run the same validation the initial correction went
+			// through: a refined suggestion can be just as empty or
+			// unavailable as the original, and it's about to be offered
+			// for execution the same way.
+			if refined.CorrectedCommand == "" {
+				fmt.Println("\n🧠 Explanation: The LLM did not return a corrected command. Keeping the previous suggestion.")
+				continue
+			}
+			if !isCommandAvailable(refined.CorrectedCommand) {
+				if *verbose {
+					fmt.Fprintf(os.Stderr, "Error: Refined command is not valid or not in PATH: %s\n", refined.CorrectedCommand)
+				}
+				fmt.Println("\n🧠 Explanation: The refined command is not valid or not in your PATH. Keeping the previous suggestion.")
+				continue
+			}
+
+			correction = refined
+			fmt.Printf("\n🧠 Explanation: %s\n", correction.Explanation)
+			fmt.Printf("🔧 Corrected: \033[1;32m%s\033[0m\n\n", correction.CorrectedCommand)
+		default:
 			fmt.Println("Aborted.")
+			return
 		}
-	} else if err := scanner.Err(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Failed to read user input: %v\n", err)
-		os.Exit(1)
-	} else {
-		// Nothing was entered, treat as "no"
-		fmt.Println("Aborted.")
 	}
 }
 
@@ -0,0 +1,126 @@
+// Package cache memoizes LLM corrections on disk, keyed by a hash of the
+// failed command, its exit code and its output, so repeat typos don't pay
+// the full LLM latency cost twice.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// defaultTTL is how long a cached entry stays valid when PFS_CACHE_TTL is
+// unset or invalid.
+const defaultTTL = 24 * time.Hour
+
+// maxKeyOutputBytes bounds how much of a command's output contributes to the
+// cache key, so two failures that differ only in a long, irrelevant tail
+// still share a cache entry.
+const maxKeyOutputBytes = 2048
+
+// Entry is a single cached correction, along with when it was stored.
+type Entry struct {
+	Explanation      string    `json:"explanation"`
+	CorrectedCommand string    `json:"corrected_command"`
+	StoredAt         time.Time `json:"stored_at"`
+}
+
+// Cache is an on-disk, JSON-backed store of previously seen corrections.
+type Cache struct {
+	path string
+	ttl  time.Duration
+}
+
+// New creates a Cache backed by ~/.cache/pfs/corrections.json, honoring
+// PFS_CACHE_TTL (a Go duration string, e.g. "24h") when set.
+func New() (*Cache, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return newCacheAt(filepath.Join(home, ".cache", "pfs", "corrections.json"), ttlFromEnv()), nil
+}
+
+func ttlFromEnv() time.Duration {
+	raw := os.Getenv("PFS_CACHE_TTL")
+	if raw == "" {
+		return defaultTTL
+	}
+	ttl, err := time.ParseDuration(raw)
+	if err != nil {
+		return defaultTTL
+	}
+	return ttl
+}
+
+func newCacheAt(path string, ttl time.Duration) *Cache {
+	return &Cache{path: path, ttl: ttl}
+}
+
+// Key derives the cache key for a failed command, truncating output so very
+// long output doesn't change the key.
+func Key(command string, exitCode int, output string) string {
+	if len(output) > maxKeyOutputBytes {
+		output = output[:maxKeyOutputBytes]
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%s", command, exitCode, output)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns the cached entry for key, if present and not expired.
+func (c *Cache) Get(key string) (Entry, bool) {
+	entries, err := c.load()
+	if err != nil {
+		return Entry{}, false
+	}
+	entry, ok := entries[key]
+	if !ok {
+		return Entry{}, false
+	}
+	if c.ttl > 0 && time.Since(entry.StoredAt) > c.ttl {
+		return Entry{}, false
+	}
+	return entry, true
+}
+
+// Set stores entry under key, creating the cache file and its parent
+// directory if necessary.
+func (c *Cache) Set(key string, entry Entry) error {
+	entries, err := c.load()
+	if err != nil {
+		entries = map[string]Entry{}
+	}
+	entries[key] = entry
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return nil
+}
+
+func (c *Cache) load() (map[string]Entry, error) {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]Entry{}, nil
+		}
+		return nil, fmt.Errorf("failed to read cache file: %w", err)
+	}
+	var entries map[string]Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cache file: %w", err)
+	}
+	return entries, nil
+}
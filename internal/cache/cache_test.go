@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache_SetAndGet(t *testing.T) {
+	c := newCacheAt(filepath.Join(t.TempDir(), "corrections.json"), time.Hour)
+
+	key := Key("gti status", 127, "gti: command not found")
+	_, ok := c.Get(key)
+	assert.False(t, ok)
+
+	entry := Entry{Explanation: "typo for git", CorrectedCommand: "git status", StoredAt: time.Now()}
+	assert.NoError(t, c.Set(key, entry))
+
+	got, ok := c.Get(key)
+	assert.True(t, ok)
+	assert.Equal(t, entry.CorrectedCommand, got.CorrectedCommand)
+}
+
+func TestCache_ExpiredEntry(t *testing.T) {
+	c := newCacheAt(filepath.Join(t.TempDir(), "corrections.json"), time.Minute)
+
+	key := Key("sl", 127, "sl: command not found")
+	entry := Entry{Explanation: "typo for ls", CorrectedCommand: "ls", StoredAt: time.Now().Add(-time.Hour)}
+	assert.NoError(t, c.Set(key, entry))
+
+	_, ok := c.Get(key)
+	assert.False(t, ok)
+}
+
+func TestKey_StableForSameInputs(t *testing.T) {
+	a := Key("gti status", 127, "not found")
+	b := Key("gti status", 127, "not found")
+	c := Key("gti status", 1, "not found")
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+}
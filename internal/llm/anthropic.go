@@ -0,0 +1,145 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// anthropicAPIVersion is the Messages API version PFS speaks.
+const anthropicAPIVersion = "2023-06-01"
+
+// AnthropicProvider implements the Provider interface for the Anthropic
+// Messages API.
+type AnthropicProvider struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	model      string
+}
+
+// NewAnthropicProvider creates a new Anthropic provider from the
+// ANTHROPIC_API_KEY, ANTHROPIC_BASE_URL and ANTHROPIC_MODEL environment
+// variables.
+func NewAnthropicProvider() (*AnthropicProvider, error) {
+	apiKey := os.Getenv("ANTHROPIC_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("ANTHROPIC_API_KEY is not set")
+	}
+	model := os.Getenv("ANTHROPIC_MODEL")
+	if model == "" {
+		return nil, fmt.Errorf("ANTHROPIC_MODEL is not set")
+	}
+	baseURL := os.Getenv("ANTHROPIC_BASE_URL")
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com"
+	}
+
+	return &AnthropicProvider{
+		httpClient: http.DefaultClient,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		model:      model,
+	}, nil
+}
+
+// ModelName returns the name of the model being used.
+func (p *AnthropicProvider) ModelName() string {
+	return p.model
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	MaxTokens int                `json:"max_tokens"`
+	Messages  []anthropicMessage `json:"messages"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+// GetCorrection sends a request to the Anthropic Messages API to correct a
+// failed shell command.
+func (p *AnthropicProvider) GetCorrection(ctx context.Context, command, output string, exitCode int, verbose bool) (*Correction, error) {
+	attempt := func(prompt string) (*Correction, error) {
+		responseText, err := p.createMessage(ctx, prompt)
+		if err != nil {
+			return nil, fmt.Errorf("Anthropic API error: %w", err)
+		}
+		if verbose {
+			fmt.Printf("\nRaw response: %s\n", responseText)
+		}
+		if responseText == "" {
+			return nil, fmt.Errorf("empty response from Anthropic")
+		}
+
+		var correction Correction
+		// Extract the JSON part of the response, as the model may include other text.
+		jsonResponse := extractJSON(responseText)
+		if jsonResponse == "" {
+			return nil, fmt.Errorf("no valid JSON found in the response from Anthropic")
+		}
+		if err := json.Unmarshal([]byte(jsonResponse), &correction); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal JSON from Anthropic response: %w", err)
+		}
+		return &correction, nil
+	}
+
+	return retryTwice(command, output, exitCode, verbose, attempt)
+}
+
+func (p *AnthropicProvider) createMessage(ctx context.Context, prompt string) (string, error) {
+	reqBody := anthropicRequest{
+		Model:     p.model,
+		MaxTokens: 1024,
+		Messages:  []anthropicMessage{{Role: "user", Content: prompt}},
+	}
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", anthropicAPIVersion)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var msgResp anthropicResponse
+	if err := json.Unmarshal(respBody, &msgResp); err != nil {
+		return "", fmt.Errorf("failed to unmarshal message response: %w", err)
+	}
+	if len(msgResp.Content) == 0 {
+		return "", fmt.Errorf("no content returned in response")
+	}
+
+	return msgResp.Content[0].Text, nil
+}
@@ -0,0 +1,37 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnthropicProvider_GetCorrection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/messages", r.URL.Path)
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "test-key", r.Header.Get("x-api-key"))
+		assert.Equal(t, anthropicAPIVersion, r.Header.Get("anthropic-version"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"content": [{"type": "text", "text": "{\"explanation\": \"mock explanation\", \"corrected_command\": \"mock command\"}"}]}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("ANTHROPIC_MODEL", "claude-3-5-sonnet-latest")
+	t.Setenv("ANTHROPIC_BASE_URL", server.URL)
+	t.Setenv("ANTHROPIC_API_KEY", "test-key")
+
+	provider, err := NewAnthropicProvider()
+	assert.NoError(t, err)
+
+	correction, err := provider.GetCorrection(context.Background(), "lş -l", "lş: invalid option -- 'l'", 1, false)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, correction)
+	assert.Equal(t, "mock explanation", correction.Explanation)
+	assert.Equal(t, "mock command", correction.CorrectedCommand)
+}
@@ -0,0 +1,37 @@
+package llm
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// NewProvider constructs the Provider configured by the PFS_PROVIDER
+// environment variable, defaulting to "ollama" for backwards compatibility
+// with existing ~/.pfs.env setups. Each backend reads its own set of
+// `<PREFIX>_API_KEY` / `<PREFIX>_BASE_URL` / `<PREFIX>_MODEL` variables.
+func NewProvider() (Provider, error) {
+	name := os.Getenv("PFS_PROVIDER")
+	if name == "" {
+		name = "ollama"
+	}
+
+	switch strings.ToLower(name) {
+	case "ollama":
+		return NewOllamaProvider()
+	case "openai":
+		return newOpenAICompatibleProviderFromEnv("OPENAI", "https://api.openai.com/v1")
+	case "groq":
+		return newOpenAICompatibleProviderFromEnv("GROQ", "https://api.groq.com/openai/v1")
+	case "openrouter":
+		return newOpenAICompatibleProviderFromEnv("OPENROUTER", "https://openrouter.ai/api/v1")
+	case "lmstudio":
+		return newOpenAICompatibleProviderFromEnv("LMSTUDIO", "http://localhost:1234/v1")
+	case "llamacpp":
+		return newOpenAICompatibleProviderFromEnv("LLAMACPP", "http://localhost:8080/v1")
+	case "anthropic":
+		return NewAnthropicProvider()
+	default:
+		return nil, fmt.Errorf("unknown PFS_PROVIDER %q", name)
+	}
+}
@@ -0,0 +1,26 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProvider_UnknownProvider(t *testing.T) {
+	t.Setenv("PFS_PROVIDER", "made-up-provider")
+
+	provider, err := NewProvider()
+
+	assert.Error(t, err)
+	assert.Nil(t, provider)
+}
+
+func TestNewProvider_OpenAIMissingModel(t *testing.T) {
+	t.Setenv("PFS_PROVIDER", "openai")
+	t.Setenv("OPENAI_MODEL", "")
+
+	provider, err := NewProvider()
+
+	assert.Error(t, err)
+	assert.Nil(t, provider)
+}
@@ -19,6 +19,18 @@ type Correction struct {
 	CorrectedCommand string `json:"corrected_command"`
 }
 
+// correctionResponseSchema constrains Ollama's structured-output mode to the
+// shape Correction expects, so the model is steered away from returning
+// conversational filler around the JSON object.
+var correctionResponseSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"explanation": {"type": "string"},
+		"corrected_command": {"type": "string"}
+	},
+	"required": ["explanation", "corrected_command"]
+}`)
+
 // OllamaProvider implements the Provider interface for local Ollama models.
 type OllamaProvider struct {
 	client *api.Client
@@ -64,10 +76,32 @@ func (p *OllamaProvider) ModelName() string {
 
 // GetCorrection sends a request to the Ollama API to correct a failed shell command.
 func (p *OllamaProvider) GetCorrection(ctx context.Context, command, output string, exitCode int, verbose bool) (*Correction, error) {
+	return p.getCorrection(ctx, command, output, exitCode, verbose, nil)
+}
+
+// GetCorrectionStream behaves like GetCorrection, but invokes onChunk with
+// each piece of text as it streams in from the model, before the full
+// response has been buffered and parsed.
+func (p *OllamaProvider) GetCorrectionStream(ctx context.Context, command, output string, exitCode int, verbose bool, onChunk func(chunk string)) (*Correction, error) {
+	return p.getCorrection(ctx, command, output, exitCode, verbose, onChunk)
+}
+
+func (p *OllamaProvider) getCorrection(ctx context.Context, command, output string, exitCode int, verbose bool, onChunk func(chunk string)) (*Correction, error) {
+	// getCorrectionText's streamed state (streamedExplanation/explanationDone)
+	// only covers a single call, so retryTwice's second attempt would replay
+	// the whole explanation from scratch. Only stream the first attempt and
+	// let the retry render silently; main already re-prints the final
+	// explanation cleanly once a correction comes back.
+	streamed := false
 	// Define a function to make an attempt, which can be retried.
 	attempt := func(prompt string) (*Correction, error) {
+		var chunkFn func(chunk string)
+		if !streamed {
+			chunkFn = onChunk
+			streamed = true
+		}
 		// No verbose output here, as the loading animation will be running.
-		responseText, tokenCount, duration, err := p.getCorrectionText(ctx, prompt)
+		responseText, tokenCount, duration, err := p.getCorrectionText(ctx, prompt, chunkFn)
 		if err != nil {
 			return nil, fmt.Errorf("Ollama API error: %w", err)
 		}
@@ -82,63 +116,192 @@ func (p *OllamaProvider) GetCorrection(ctx context.Context, command, output stri
 		}
 
 		var correction Correction
-		// Extract the JSON part of the response, as the model may include other text.
-		jsonResponse := extractJSON(responseText)
-		if jsonResponse == "" {
-			return nil, fmt.Errorf("no valid JSON found in the response from Ollama")
-		}
-
-		if err := json.Unmarshal([]byte(jsonResponse), &correction); err != nil {
-			// If the JSON is malformed, also treat it as a failure.
-			return nil, fmt.Errorf("failed to unmarshal JSON from Ollama response: %w", err)
+		// Ollama has historically returned a leading space in JSON mode.
+		trimmed := strings.TrimLeft(responseText, " \t\r\n")
+		if err := json.Unmarshal([]byte(trimmed), &correction); err != nil {
+			// The model didn't honor the format hint; fall back to extracting
+			// the JSON object from whatever text it returned.
+			jsonResponse := extractJSON(responseText)
+			if jsonResponse == "" {
+				return nil, fmt.Errorf("no valid JSON found in the response from Ollama")
+			}
+			if err := json.Unmarshal([]byte(jsonResponse), &correction); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal JSON from Ollama response: %w", err)
+			}
 		}
 
 		// Success!
 		return &correction, nil
 	}
 
-	// First attempt with the standard prompt.
-	prompt := buildPrompt(command, output, exitCode)
-	correction, err := attempt(prompt)
-	if err == nil && correction != nil && correction.CorrectedCommand != "" {
-		return correction, nil // Success on the first try.
+	return retryTwice(command, output, exitCode, verbose, attempt)
+}
+
+// chatSystemPrompt keeps a multi-turn refinement conversation anchored to the
+// same response shape used by GetCorrection.
+const chatSystemPrompt = `You are a command-line expert helping a user fix a failed shell command.
+Keep refining your suggestion based on the user's follow-up messages.
+Your response MUST always be a single, raw JSON object with two keys: "corrected_command" and "explanation".
+Do NOT include any other text, markdown, or conversational filler.`
+
+// Chat sends the full conversation history to Ollama's chat endpoint and
+// returns the model's next correction, allowing a user to iteratively
+// refine a suggestion (e.g. "no, I meant on the remote host").
+func (p *OllamaProvider) Chat(ctx context.Context, history []Message) (*Correction, error) {
+	messages := make([]api.Message, 0, len(history)+1)
+	messages = append(messages, api.Message{Role: "system", Content: chatSystemPrompt})
+	for _, m := range history {
+		messages = append(messages, api.Message{Role: m.Role, Content: m.Content})
 	}
-	if err != nil && verbose {
-		fmt.Printf("First attempt failed with error: %v\n", err)
+
+	stream := false
+	req := &api.ChatRequest{
+		Model:    p.model,
+		Messages: messages,
+		Stream:   &stream,
+		Format:   correctionResponseSchema,
+		Options: map[string]interface{}{
+			"temperature": 0,
+		},
+	}
+
+	var responseText string
+	respFunc := func(resp api.ChatResponse) error {
+		responseText = resp.Message.Content
+		return nil
 	}
 
-	// If the first attempt failed (or returned an empty correction), retry with a more insistent prompt.
-	prompt = buildRetryPrompt(command, output, exitCode)
-	correction, err = attempt(prompt)
-	if err == nil && correction != nil && correction.CorrectedCommand != "" {
-		return correction, nil // Success on the second try.
+	if err := p.client.Chat(ctx, req, respFunc); err != nil {
+		return nil, fmt.Errorf("ollama API error: %w", err)
 	}
-	if err != nil && verbose {
-		fmt.Printf("Second attempt failed with error: %v\n", err)
+	if responseText == "" {
+		return nil, fmt.Errorf("empty response from Ollama")
 	}
 
-	// If both attempts fail, return a clear error message to the user.
-	return nil, fmt.Errorf("the language model did not return a valid correction after two attempts")
+	var correction Correction
+	// Ollama has historically returned a leading space in JSON mode.
+	trimmed := strings.TrimLeft(responseText, " \t\r\n")
+	if err := json.Unmarshal([]byte(trimmed), &correction); err != nil {
+		jsonResponse := extractJSON(responseText)
+		if jsonResponse == "" {
+			return nil, fmt.Errorf("no valid JSON found in the response from Ollama")
+		}
+		if err := json.Unmarshal([]byte(jsonResponse), &correction); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal JSON from Ollama response: %w", err)
+		}
+	}
+
+	return &correction, nil
 }
 
-func (p *OllamaProvider) getCorrectionText(ctx context.Context, prompt string) (string, int, time.Duration, error) {
+// maxToolIterations bounds how many tool-call round trips GetCorrectionWithTools
+// will make before giving up, so a model that never stops calling tools can't
+// hang the CLI.
+const maxToolIterations = 5
+
+// GetCorrectionWithTools behaves like GetCorrection, but gives the model
+// tools (check_command_exists, explain_manpage, dry_run) it can call
+// mid-reasoning via Ollama's function-calling support, feeding each tool's
+// result back until the model returns a final answer with no further calls.
+func (p *OllamaProvider) GetCorrectionWithTools(ctx context.Context, command, output string, exitCode int, verbose bool) (*Correction, error) {
+	messages := []api.Message{
+		{Role: "system", Content: chatSystemPrompt},
+		{Role: "user", Content: buildPrompt(command, output, exitCode)},
+	}
+
 	stream := false
+	for i := 0; i < maxToolIterations; i++ {
+		req := &api.ChatRequest{
+			Model:    p.model,
+			Messages: messages,
+			Stream:   &stream,
+			Tools:    correctionTools,
+			Options: map[string]interface{}{
+				"temperature": 0,
+			},
+		}
+
+		var reply api.Message
+		respFunc := func(resp api.ChatResponse) error {
+			reply = resp.Message
+			return nil
+		}
+		if err := p.client.Chat(ctx, req, respFunc); err != nil {
+			return nil, fmt.Errorf("ollama API error: %w", err)
+		}
+
+		if len(reply.ToolCalls) == 0 {
+			if reply.Content == "" {
+				return nil, fmt.Errorf("empty response from Ollama")
+			}
+
+			var correction Correction
+			trimmed := strings.TrimLeft(reply.Content, " \t\r\n")
+			if err := json.Unmarshal([]byte(trimmed), &correction); err != nil {
+				jsonResponse := extractJSON(reply.Content)
+				if jsonResponse == "" {
+					return nil, fmt.Errorf("no valid JSON found in the response from Ollama")
+				}
+				if err := json.Unmarshal([]byte(jsonResponse), &correction); err != nil {
+					return nil, fmt.Errorf("failed to unmarshal JSON from Ollama response: %w", err)
+				}
+			}
+			return &correction, nil
+		}
+
+		messages = append(messages, reply)
+		for _, call := range reply.ToolCalls {
+			result := runTool(call.Function)
+			if verbose {
+				fmt.Printf("\nTool call: %s(%v) -> %s\n", call.Function.Name, call.Function.Arguments, result)
+			}
+			messages = append(messages, api.Message{Role: "tool", Content: result, ToolName: call.Function.Name})
+		}
+	}
+
+	return nil, fmt.Errorf("the language model did not return a final correction after %d tool-calling rounds", maxToolIterations)
+}
+
+// getCorrectionText streams the generate request from Ollama, accumulating
+// the response chunks. If onChunk is non-nil, it is invoked with each chunk
+// as it arrives so callers can render token-by-token progress.
+func (p *OllamaProvider) getCorrectionText(ctx context.Context, prompt string, onChunk func(chunk string)) (string, int, time.Duration, error) {
+	stream := true
 	req := &api.GenerateRequest{
 		Model:  p.model,
 		Prompt: prompt,
 		Stream: &stream,
+		Format: correctionResponseSchema,
 		Options: map[string]interface{}{
 			"temperature": 0,
 		},
 	}
 
-	var responseText string
+	var responseText strings.Builder
 	var evalCount int
 	var evalDuration time.Duration
+	// In JSON mode the raw stream is the structured-output object itself
+	// (field names, quotes and braces included), not prose. Decode just the
+	// "explanation" string value incrementally so onChunk sees clean text
+	// instead of JSON syntax, and stop forwarding once that value closes.
+	var streamedExplanation string
+	var explanationDone bool
 	respFunc := func(resp api.GenerateResponse) error {
-		responseText = resp.Response
-		evalCount = resp.EvalCount
-		evalDuration = resp.EvalDuration
+		if resp.Response != "" {
+			responseText.WriteString(resp.Response)
+			if onChunk != nil && !explanationDone {
+				explanation, complete := partialJSONStringValue(responseText.String(), "explanation")
+				if len(explanation) > len(streamedExplanation) {
+					onChunk(explanation[len(streamedExplanation):])
+					streamedExplanation = explanation
+				}
+				explanationDone = complete
+			}
+		}
+		if resp.Done {
+			evalCount = resp.EvalCount
+			evalDuration = resp.EvalDuration
+		}
 		return nil
 	}
 
@@ -146,12 +309,13 @@ func (p *OllamaProvider) getCorrectionText(ctx context.Context, prompt string) (
 		return "", 0, 0, fmt.Errorf("ollama API error: %w", err)
 	}
 
+	text := responseText.String()
 	// Check if the response is HTML, which might indicate a captive portal or proxy error.
-	if strings.HasPrefix(strings.TrimSpace(responseText), "<!DOCTYPE html>") {
+	if strings.HasPrefix(strings.TrimSpace(text), "<!DOCTYPE html>") {
 		return "", 0, 0, fmt.Errorf("received an HTML response instead of JSON. Check for captive portals or network proxy issues")
 	}
 
-	return responseText, evalCount, evalDuration, nil
+	return text, evalCount, evalDuration, nil
 }
 
 // buildPrompt constructs the initial prompt for the LLM.
@@ -185,6 +349,73 @@ func extractJSON(s string) string {
 	return s[start : end+1]
 }
 
+// partialJSONStringValue decodes as much as has arrived so far of the JSON
+// string value for the given key in a (possibly incomplete) JSON object,
+// unescaping standard JSON escape sequences. complete reports whether the
+// closing quote has been seen yet. An escape sequence that has only partly
+// arrived is left out of value until the rest of it arrives, so value only
+// ever grows as more of raw comes in.
+func partialJSONStringValue(raw, key string) (value string, complete bool) {
+	idx := strings.Index(raw, `"`+key+`"`)
+	if idx == -1 {
+		return "", false
+	}
+	rest := raw[idx+len(key)+2:]
+
+	colon := strings.IndexByte(rest, ':')
+	if colon == -1 {
+		return "", false
+	}
+	rest = strings.TrimLeft(rest[colon+1:], " \t\r\n")
+	if rest == "" || rest[0] != '"' {
+		return "", false
+	}
+	rest = rest[1:]
+
+	var b strings.Builder
+	for i := 0; i < len(rest); i++ {
+		c := rest[i]
+		if c == '"' {
+			return b.String(), true
+		}
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		// Escape sequence; wait for it to fully arrive before decoding it.
+		if i+1 >= len(rest) {
+			return b.String(), false
+		}
+		i++
+		switch rest[i] {
+		case '"', '\\', '/':
+			b.WriteByte(rest[i])
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+4 >= len(rest) {
+				return b.String(), false
+			}
+			var r rune
+			if _, err := fmt.Sscanf(rest[i+1:i+5], "%04x", &r); err == nil {
+				b.WriteRune(r)
+			}
+			i += 4
+		default:
+			b.WriteByte(rest[i])
+		}
+	}
+	return b.String(), false
+}
+
 // buildRetryPrompt constructs a more insistent prompt for the LLM.
 func buildRetryPrompt(command, output string, exitCode int) string {
 	return fmt.Sprintf(
@@ -0,0 +1,41 @@
+//go:build integration
+
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOllamaProvider_GetCorrection_Integration exercises GetCorrection
+// against a real Ollama server, catching protocol regressions (streaming
+// semantics, JSON-mode quirks, model-not-found errors) that a mocked
+// httptest server can't. Run with: go test -tags integration ./internal/llm/...
+func TestOllamaProvider_GetCorrection_Integration(t *testing.T) {
+	ctx := context.Background()
+	provider, container, err := NewTestOllamaProvider(ctx, "qwen2.5:0.5b")
+	require.NoError(t, err)
+	defer container.Terminate(ctx)
+
+	cases := []struct {
+		name     string
+		command  string
+		output   string
+		exitCode int
+	}{
+		{"typo ls", "lsa", "lsa: command not found", 127},
+		{"typo git", "gti status", "gti: command not found", 127},
+		{"typo python", "pytohn -V", "pytohn: command not found", 127},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			correction, err := provider.GetCorrection(ctx, tc.command, tc.output, tc.exitCode, false)
+			require.NoError(t, err)
+			assert.NotEmpty(t, correction.CorrectedCommand)
+		})
+	}
+}
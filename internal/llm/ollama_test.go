@@ -46,6 +46,211 @@ func TestOllamaProvider_GetCorrection(t *testing.T) {
 	assert.Equal(t, "mock command", correction.CorrectedCommand)
 }
 
+func TestOllamaProvider_GetCorrection_LeadingSpaceAndFallback(t *testing.T) {
+	// Ollama has historically returned a leading space in JSON mode, and some
+	// models ignore the format hint entirely and wrap the JSON in prose.
+	responses := []string{
+		` {"explanation": "mock explanation", "corrected_command": "mock command"}`,
+		"Sure, here you go:\n" + `{"explanation": "mock explanation", "corrected_command": "mock command"}`,
+	}
+
+	for _, response := range responses {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			mockResponse := api.GenerateResponse{
+				Response: response,
+				Done:     true,
+			}
+			json.NewEncoder(w).Encode(mockResponse)
+		}))
+
+		parsedURL, err := url.Parse(server.URL)
+		assert.NoError(t, err)
+		client := api.NewClient(parsedURL, http.DefaultClient)
+		provider := &OllamaProvider{client: client, model: "deepseek-r1:1.5b"}
+
+		correction, err := provider.GetCorrection(context.Background(), "lş -l", "lş: invalid option -- 'l'", 1, false)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, correction)
+		assert.Equal(t, "mock explanation", correction.Explanation)
+		assert.Equal(t, "mock command", correction.CorrectedCommand)
+
+		server.Close()
+	}
+}
+
+func TestOllamaProvider_GetCorrectionStream(t *testing.T) {
+	// Simulate Ollama streaming the response in multiple chunks, newline-delimited.
+	chunks := []api.GenerateResponse{
+		{Response: `{"explanation": "mock `},
+		{Response: `explanation", "corrected_command": "mock command"}`, Done: true, Metrics: api.Metrics{EvalCount: 7}},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		encoder := json.NewEncoder(w)
+		for _, chunk := range chunks {
+			assert.NoError(t, encoder.Encode(chunk))
+		}
+	}))
+	defer server.Close()
+
+	parsedURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	client := api.NewClient(parsedURL, http.DefaultClient)
+	provider := &OllamaProvider{client: client, model: "deepseek-r1:1.5b"}
+
+	var streamed string
+	correction, err := provider.GetCorrectionStream(context.Background(), "lş -l", "lş: invalid option -- 'l'", 1, false, func(chunk string) {
+		streamed += chunk
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, correction)
+	assert.Equal(t, "mock explanation", correction.Explanation)
+	assert.Equal(t, "mock command", correction.CorrectedCommand)
+	// onChunk should only see the decoded explanation text, not the raw
+	// JSON syntax (field names, quotes, braces) or the corrected_command.
+	assert.Equal(t, "mock explanation", streamed)
+}
+
+func TestOllamaProvider_GetCorrectionStream_RetryDoesNotReplayStream(t *testing.T) {
+	// A first attempt that parses but returns no corrected_command triggers
+	// retryTwice's retry. The retry must not re-stream the explanation.
+	round := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		round++
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		resp := api.GenerateResponse{Done: true}
+		if round == 1 {
+			resp.Response = `{"explanation": "first explanation", "corrected_command": ""}`
+		} else {
+			resp.Response = `{"explanation": "second explanation", "corrected_command": "mock command"}`
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer server.Close()
+
+	parsedURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	client := api.NewClient(parsedURL, http.DefaultClient)
+	provider := &OllamaProvider{client: client, model: "deepseek-r1:1.5b"}
+
+	var streamed string
+	correction, err := provider.GetCorrectionStream(context.Background(), "lş -l", "lş: invalid option -- 'l'", 1, false, func(chunk string) {
+		streamed += chunk
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, correction)
+	assert.Equal(t, "second explanation", correction.Explanation)
+	assert.Equal(t, "mock command", correction.CorrectedCommand)
+	assert.Equal(t, 2, round)
+	// Only the first attempt streams; the retry renders silently instead of
+	// replaying its explanation right after the first one.
+	assert.Equal(t, "first explanation", streamed)
+}
+
+func TestPartialJSONStringValue(t *testing.T) {
+	value, complete := partialJSONStringValue(`{"explanation": "mock `, "explanation")
+	assert.Equal(t, "mock ", value)
+	assert.False(t, complete)
+
+	value, complete = partialJSONStringValue(`{"explanation": "mock explanation", "corrected_command": "mock command"}`, "explanation")
+	assert.Equal(t, "mock explanation", value)
+	assert.True(t, complete)
+
+	value, complete = partialJSONStringValue(`{"explanation": "line\nbreak \"quoted\""}`, "explanation")
+	assert.Equal(t, "line\nbreak \"quoted\"", value)
+	assert.True(t, complete)
+
+	value, complete = partialJSONStringValue(`{"corrected_command": "x"}`, "explanation")
+	assert.Equal(t, "", value)
+	assert.False(t, complete)
+}
+
+func TestOllamaProvider_Chat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/chat", r.URL.Path)
+		assert.Equal(t, "POST", r.Method)
+
+		var req api.ChatRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		// System prompt, then the seeded user/assistant turns, then the refinement.
+		assert.Len(t, req.Messages, 4)
+		assert.Equal(t, "system", req.Messages[0].Role)
+		assert.Equal(t, "use sudo", req.Messages[3].Content)
+
+		w.Header().Set("Content-Type", "application/json")
+		mockResponse := api.ChatResponse{
+			Message: api.Message{Role: "assistant", Content: `{"explanation": "refined explanation", "corrected_command": "sudo mock command"}`},
+			Done:    true,
+		}
+		json.NewEncoder(w).Encode(mockResponse)
+	}))
+	defer server.Close()
+
+	parsedURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	client := api.NewClient(parsedURL, http.DefaultClient)
+	provider := &OllamaProvider{client: client, model: "deepseek-r1:1.5b"}
+
+	history := []Message{
+		{Role: "user", Content: "lş -l failed with exit code 1"},
+		{Role: "assistant", Content: `{"explanation": "mock explanation", "corrected_command": "mock command"}`},
+		{Role: "user", Content: "use sudo"},
+	}
+	correction, err := provider.Chat(context.Background(), history)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, correction)
+	assert.Equal(t, "refined explanation", correction.Explanation)
+	assert.Equal(t, "sudo mock command", correction.CorrectedCommand)
+}
+
+func TestOllamaProvider_GetCorrectionWithTools(t *testing.T) {
+	round := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/chat", r.URL.Path)
+		round++
+
+		w.Header().Set("Content-Type", "application/json")
+		var mockResponse api.ChatResponse
+		if round == 1 {
+			// First round: the model asks whether "git" exists before answering.
+			mockResponse = api.ChatResponse{
+				Message: api.Message{
+					Role: "assistant",
+					ToolCalls: []api.ToolCall{
+						{Function: api.ToolCallFunction{Name: "check_command_exists", Arguments: api.ToolCallFunctionArguments{"name": "git"}}},
+					},
+				},
+			}
+		} else {
+			mockResponse = api.ChatResponse{
+				Message: api.Message{Role: "assistant", Content: `{"explanation": "mock explanation", "corrected_command": "git status"}`},
+				Done:    true,
+			}
+		}
+		json.NewEncoder(w).Encode(mockResponse)
+	}))
+	defer server.Close()
+
+	parsedURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	client := api.NewClient(parsedURL, http.DefaultClient)
+	provider := &OllamaProvider{client: client, model: "deepseek-r1:1.5b"}
+
+	correction, err := provider.GetCorrectionWithTools(context.Background(), "gti status", "gti: command not found", 127, false)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, correction)
+	assert.Equal(t, "mock explanation", correction.Explanation)
+	assert.Equal(t, "git status", correction.CorrectedCommand)
+	assert.Equal(t, 2, round)
+}
+
 func TestNewOllamaProvider_HTTPS(t *testing.T) {
 	// Create a mock TLS server to simulate an HTTPS endpoint.
 	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
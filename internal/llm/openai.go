@@ -0,0 +1,147 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// OpenAICompatibleProvider implements the Provider interface for any backend
+// that speaks the OpenAI chat completions schema: OpenAI itself, Groq,
+// OpenRouter, LM Studio and the llama.cpp server all qualify.
+type OpenAICompatibleProvider struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	model      string
+}
+
+// newOpenAICompatibleProviderFromEnv builds an OpenAICompatibleProvider from
+// the `<prefix>_API_KEY`, `<prefix>_BASE_URL` and `<prefix>_MODEL`
+// environment variables, falling back to defaultBaseURL when
+// `<prefix>_BASE_URL` is unset.
+func newOpenAICompatibleProviderFromEnv(prefix, defaultBaseURL string) (*OpenAICompatibleProvider, error) {
+	model := os.Getenv(prefix + "_MODEL")
+	if model == "" {
+		return nil, fmt.Errorf("%s_MODEL is not set", prefix)
+	}
+	baseURL := os.Getenv(prefix + "_BASE_URL")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	return &OpenAICompatibleProvider{
+		httpClient: http.DefaultClient,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     os.Getenv(prefix + "_API_KEY"),
+		model:      model,
+	}, nil
+}
+
+// ModelName returns the name of the model being used.
+func (p *OpenAICompatibleProvider) ModelName() string {
+	return p.model
+}
+
+type openAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIResponseFormat struct {
+	Type string `json:"type"`
+}
+
+type openAIChatRequest struct {
+	Model          string                `json:"model"`
+	Messages       []openAIChatMessage   `json:"messages"`
+	Temperature    float64               `json:"temperature"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message openAIChatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+// GetCorrection sends a request to the OpenAI-compatible chat completions
+// endpoint to correct a failed shell command.
+func (p *OpenAICompatibleProvider) GetCorrection(ctx context.Context, command, output string, exitCode int, verbose bool) (*Correction, error) {
+	attempt := func(prompt string) (*Correction, error) {
+		responseText, err := p.chatCompletion(ctx, prompt)
+		if err != nil {
+			return nil, fmt.Errorf("OpenAI-compatible API error: %w", err)
+		}
+		if verbose {
+			fmt.Printf("\nRaw response: %s\n", responseText)
+		}
+		if responseText == "" {
+			return nil, fmt.Errorf("empty response from provider")
+		}
+
+		var correction Correction
+		// Extract the JSON part of the response, as the model may include other text.
+		jsonResponse := extractJSON(responseText)
+		if jsonResponse == "" {
+			return nil, fmt.Errorf("no valid JSON found in the response")
+		}
+		if err := json.Unmarshal([]byte(jsonResponse), &correction); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal JSON response: %w", err)
+		}
+		return &correction, nil
+	}
+
+	return retryTwice(command, output, exitCode, verbose, attempt)
+}
+
+func (p *OpenAICompatibleProvider) chatCompletion(ctx context.Context, prompt string) (string, error) {
+	reqBody := openAIChatRequest{
+		Model:          p.model,
+		Messages:       []openAIChatMessage{{Role: "user", Content: prompt}},
+		Temperature:    0,
+		ResponseFormat: &openAIResponseFormat{Type: "json_object"},
+	}
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	var chatResp openAIChatResponse
+	if err := json.Unmarshal(respBody, &chatResp); err != nil {
+		return "", fmt.Errorf("failed to unmarshal chat completion response: %w", err)
+	}
+	if len(chatResp.Choices) == 0 {
+		return "", fmt.Errorf("no choices returned in response")
+	}
+
+	return chatResp.Choices[0].Message.Content, nil
+}
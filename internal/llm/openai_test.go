@@ -0,0 +1,36 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAICompatibleProvider_GetCorrection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/chat/completions", r.URL.Path)
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "{\"explanation\": \"mock explanation\", \"corrected_command\": \"mock command\"}"}}]}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("OPENAI_MODEL", "gpt-4o-mini")
+	t.Setenv("OPENAI_BASE_URL", server.URL)
+	t.Setenv("OPENAI_API_KEY", "test-key")
+
+	provider, err := newOpenAICompatibleProviderFromEnv("OPENAI", "https://api.openai.com/v1")
+	assert.NoError(t, err)
+
+	correction, err := provider.GetCorrection(context.Background(), "lş -l", "lş: invalid option -- 'l'", 1, false)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, correction)
+	assert.Equal(t, "mock explanation", correction.Explanation)
+	assert.Equal(t, "mock command", correction.CorrectedCommand)
+}
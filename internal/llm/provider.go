@@ -0,0 +1,83 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+)
+
+// Provider is implemented by every LLM backend PFS can ask for a command
+// correction. Selection between backends is driven by NewProvider.
+type Provider interface {
+	// ModelName returns the name of the model being used.
+	ModelName() string
+	// GetCorrection asks the LLM to analyze a failed command and suggest a fix.
+	GetCorrection(ctx context.Context, command, output string, exitCode int, verbose bool) (*Correction, error)
+}
+
+// Message represents a single turn in a multi-turn refinement conversation.
+type Message struct {
+	Role    string // "user" or "assistant"
+	Content string
+}
+
+// ChatProvider is implemented by providers that support multi-turn
+// conversational refinement of a correction. Callers should type-assert for
+// this capability and fall back to a one-shot GetCorrection when it's absent.
+type ChatProvider interface {
+	Provider
+	// Chat sends the full conversation history to the model and returns its
+	// next correction.
+	Chat(ctx context.Context, history []Message) (*Correction, error)
+}
+
+// ToolCallingProvider is implemented by providers that can let the model
+// consult local validators mid-reasoning (tool/function calling) instead of
+// only being checked after the fact.
+type ToolCallingProvider interface {
+	Provider
+	// GetCorrectionWithTools behaves like GetCorrection, but gives the model
+	// tools to check command existence, read man pages, and dry-run `--help`
+	// before committing to a final correction.
+	GetCorrectionWithTools(ctx context.Context, command, output string, exitCode int, verbose bool) (*Correction, error)
+}
+
+// StreamingProvider is implemented by providers that can report generation
+// progress as it streams in, rather than only returning once the full
+// response has been buffered. Callers should type-assert for this
+// capability and fall back to Provider.GetCorrection when it's absent.
+type StreamingProvider interface {
+	Provider
+	// GetCorrectionStream behaves like GetCorrection, invoking onChunk with
+	// each piece of text as it arrives from the model.
+	GetCorrectionStream(ctx context.Context, command, output string, exitCode int, verbose bool, onChunk func(chunk string)) (*Correction, error)
+}
+
+// retryTwice runs attempt with the standard prompt and, if it fails or
+// returns an empty correction, retries once with a more insistent prompt.
+// Every Provider.GetCorrection implementation follows this same two-attempt
+// policy to tolerate a model that ignores the requested response format, so
+// it's factored out here rather than duplicated per backend.
+func retryTwice(command, output string, exitCode int, verbose bool, attempt func(prompt string) (*Correction, error)) (*Correction, error) {
+	// First attempt with the standard prompt.
+	prompt := buildPrompt(command, output, exitCode)
+	correction, err := attempt(prompt)
+	if err == nil && correction != nil && correction.CorrectedCommand != "" {
+		return correction, nil // Success on the first try.
+	}
+	if err != nil && verbose {
+		fmt.Printf("First attempt failed with error: %v\n", err)
+	}
+
+	// If the first attempt failed (or returned an empty correction), retry with a more insistent prompt.
+	prompt = buildRetryPrompt(command, output, exitCode)
+	correction, err = attempt(prompt)
+	if err == nil && correction != nil && correction.CorrectedCommand != "" {
+		return correction, nil // Success on the second try.
+	}
+	if err != nil && verbose {
+		fmt.Printf("Second attempt failed with error: %v\n", err)
+	}
+
+	// If both attempts fail, return a clear error message to the user.
+	return nil, fmt.Errorf("the language model did not return a valid correction after two attempts")
+}
@@ -0,0 +1,75 @@
+//go:build integration
+
+package llm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// NewTestOllamaProvider starts a real ollama/ollama container, pulls model
+// into it, and returns an OllamaProvider bound to the container's
+// dynamically mapped port. The caller is responsible for terminating the
+// returned container once done with it.
+func NewTestOllamaProvider(ctx context.Context, model string) (*OllamaProvider, testcontainers.Container, error) {
+	req := testcontainers.ContainerRequest{
+		Image:        "ollama/ollama:latest",
+		ExposedPorts: []string{"11434/tcp"},
+		WaitingFor:   wait.ForHTTP("/").WithPort("11434/tcp"),
+	}
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to start ollama container: %w", err)
+	}
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		return nil, container, fmt.Errorf("failed to get container host: %w", err)
+	}
+	port, err := container.MappedPort(ctx, "11434")
+	if err != nil {
+		return nil, container, fmt.Errorf("failed to get mapped port: %w", err)
+	}
+	baseURL := fmt.Sprintf("http://%s:%s", host, port.Port())
+
+	if err := pullModel(ctx, baseURL, model); err != nil {
+		return nil, container, fmt.Errorf("failed to pull model %q: %w", model, err)
+	}
+
+	provider, err := newOllamaProviderWithClient(baseURL, model, http.DefaultClient)
+	if err != nil {
+		return nil, container, fmt.Errorf("failed to create ollama provider: %w", err)
+	}
+	return provider, container, nil
+}
+
+// pullModel blocks until the container has finished pulling model.
+func pullModel(ctx context.Context, baseURL, model string) error {
+	body := strings.NewReader(fmt.Sprintf(`{"name": %q, "stream": false}`, model))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/api/pull", body)
+	if err != nil {
+		return fmt.Errorf("failed to build pull request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("pull request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
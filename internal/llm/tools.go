@@ -0,0 +1,133 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/ollama/ollama/api"
+)
+
+// correctionTools describes the local validators the model can invoke while
+// reasoning about a correction: whether a command exists, what its man page
+// says, and whether `--help` succeeds without actually running it.
+var correctionTools = mustParseTools(`[
+	{
+		"type": "function",
+		"function": {
+			"name": "check_command_exists",
+			"description": "Check whether a command name is available in the system's PATH.",
+			"parameters": {
+				"type": "object",
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string", "description": "The command name to look up, e.g. \"git\"."}
+				}
+			}
+		}
+	},
+	{
+		"type": "function",
+		"function": {
+			"name": "explain_manpage",
+			"description": "Return the opening lines of 'man <cmd>', if a manual page exists.",
+			"parameters": {
+				"type": "object",
+				"required": ["cmd"],
+				"properties": {
+					"cmd": {"type": "string", "description": "The command to look up a manual page for, e.g. \"ls\"."}
+				}
+			}
+		}
+	},
+	{
+		"type": "function",
+		"function": {
+			"name": "dry_run",
+			"description": "Run the command's program (the first word of cmd) with '--help' to sanity-check it; the rest of cmd is not passed.",
+			"parameters": {
+				"type": "object",
+				"required": ["cmd"],
+				"properties": {
+					"cmd": {"type": "string", "description": "The full command line to dry-run, e.g. \"ls -la\"."}
+				}
+			}
+		}
+	}
+]`)
+
+func mustParseTools(raw string) api.Tools {
+	var tools api.Tools
+	if err := json.Unmarshal([]byte(raw), &tools); err != nil {
+		panic(fmt.Sprintf("llm: invalid correction tools schema: %v", err))
+	}
+	return tools
+}
+
+// runTool executes a tool call locally and returns the text to feed back to
+// the model as the tool's result.
+func runTool(call api.ToolCallFunction) string {
+	switch call.Name {
+	case "check_command_exists":
+		name, _ := call.Arguments["name"].(string)
+		return checkCommandExists(name)
+	case "explain_manpage":
+		cmd, _ := call.Arguments["cmd"].(string)
+		return explainManpage(cmd)
+	case "dry_run":
+		cmd, _ := call.Arguments["cmd"].(string)
+		return dryRun(cmd)
+	default:
+		return fmt.Sprintf("unknown tool %q", call.Name)
+	}
+}
+
+// checkCommandExists reports whether the first word of name is in PATH.
+func checkCommandExists(name string) string {
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return "no command name provided"
+	}
+	if _, err := exec.LookPath(fields[0]); err != nil {
+		return fmt.Sprintf("%q is not available in PATH", fields[0])
+	}
+	return fmt.Sprintf("%q is available in PATH", fields[0])
+}
+
+// firstLines returns at most n lines of s, for feeding truncated tool output
+// back to the model without blowing up the context.
+func firstLines(s string, n int) string {
+	lines := strings.SplitN(s, "\n", n+1)
+	if len(lines) > n {
+		lines = lines[:n]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// explainManpage returns the opening lines of the command's man page, if any.
+func explainManpage(cmd string) string {
+	fields := strings.Fields(cmd)
+	if len(fields) == 0 {
+		return "no command provided"
+	}
+	out, err := exec.Command("man", fields[0]).CombinedOutput()
+	if err != nil {
+		return fmt.Sprintf("no manual page found for %q", fields[0])
+	}
+	return firstLines(string(out), 10)
+}
+
+// dryRun runs the command's `--help` flag to sanity-check it without any
+// real side effects.
+func dryRun(cmd string) string {
+	fields := strings.Fields(cmd)
+	if len(fields) == 0 {
+		return "no command provided"
+	}
+	out, err := exec.Command(fields[0], "--help").CombinedOutput()
+	if err != nil {
+		return fmt.Sprintf("%q --help failed: %v\n%s", fields[0], err, firstLines(string(out), 10))
+	}
+	return firstLines(string(out), 10)
+}
@@ -0,0 +1,18 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/ollama/ollama/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCommandExists(t *testing.T) {
+	assert.Contains(t, checkCommandExists("ls -la"), "is available in PATH")
+	assert.Contains(t, checkCommandExists("not-a-real-command-xyz"), "is not available in PATH")
+}
+
+func TestRunTool_UnknownTool(t *testing.T) {
+	result := runTool(api.ToolCallFunction{Name: "not_a_real_tool"})
+	assert.Contains(t, result, "unknown tool")
+}